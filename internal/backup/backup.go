@@ -0,0 +1,83 @@
+// Package backup archives a directory to a timestamped .tar.gz before
+// install/uninstall performs an operation that could overwrite or delete it.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TimestampedName returns a path under destDir for a backup of dir, named
+// after dir's base name and the current time, e.g.
+// "/backups/GoSublime-20260726-231045.tar.gz".
+func TimestampedName(destDir, dir string) string {
+	name := fmt.Sprintf("%s-%s.tar.gz", filepath.Base(dir), time.Now().Format("20060102-150405"))
+	return filepath.Join(destDir, name)
+}
+
+// Archive writes a gzipped tar of dir to destPath, keeping dir's base name
+// as the top-level entry so extracting the archive restores it under the
+// same name.
+func Archive(dir, destPath string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	base := filepath.Base(dir)
+	return filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := base
+		if rel != "." {
+			name = filepath.Join(base, rel)
+		}
+
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+}
@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTimestampedName(t *testing.T) {
+	name := TimestampedName("/backups", "/packages/GoSublime")
+	if filepath.Dir(name) != "/backups" {
+		t.Errorf("TimestampedName dir = %q, want /backups", filepath.Dir(name))
+	}
+	if !strings.HasPrefix(filepath.Base(name), "GoSublime-") || !strings.HasSuffix(name, ".tar.gz") {
+		t.Errorf("TimestampedName = %q, want GoSublime-<ts>.tar.gz", name)
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "GoSublime")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "margo.go"), []byte("package margo\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "nested.go"), []byte("package sub\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "GoSublime.tar.gz")
+	if err := Archive(src, dest); err != nil {
+		t.Fatalf("Archive: %s", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %s", err)
+	}
+	defer gz.Close()
+
+	got := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar.Next: %s", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("ReadAll: %s", err)
+		}
+		got[hdr.Name] = string(body)
+	}
+
+	want := map[string]string{
+		"GoSublime/margo.go":      "package margo\n",
+		"GoSublime/sub/nested.go": "package sub\n",
+	}
+	for name, body := range want {
+		if got[name] != body {
+			t.Errorf("archive entry %q = %q, want %q", name, got[name], body)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("archive has %d file entries, want %d (%v)", len(got), len(want), got)
+	}
+}
+
+func TestArchiveRejectsNonDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	if err := Archive(file, filepath.Join(t.TempDir(), "out.tar.gz")); err == nil {
+		t.Error("Archive on a non-directory: got nil error, want one")
+	}
+}
@@ -0,0 +1,99 @@
+// Package editor abstracts over the various Sublime Text generations (and
+// forks) that GoSublime can be installed into, so that the install/uninstall
+// commands don't need to hard-code a single Packages directory layout.
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Editor resolves the Packages directory that GoSublime should be installed
+// into for a particular editor backend.
+type Editor interface {
+	// Name identifies the backend, e.g. "sublime_text_3".
+	Name() string
+
+	// PackagesDir returns the absolute path to the editor's Packages
+	// directory, validating that it exists.
+	PackagesDir() (string, error)
+}
+
+// SublimeText3 targets Sublime Text 3's Packages directory.
+type SublimeText3 struct{}
+
+func (SublimeText3) Name() string { return "sublime_text_3" }
+
+func (SublimeText3) PackagesDir() (string, error) {
+	return packagesDirFromMap(map[string]string{
+		"linux":   "$HOME/.config/sublime-text-3/Packages",
+		"darwin":  "$HOME/Library/Application Support/Sublime Text 3/Packages",
+		"windows": "$APPDATA\\Sublime Text 3\\Packages",
+	})
+}
+
+// SublimeText4 targets Sublime Text 4's Packages directory.
+type SublimeText4 struct{}
+
+func (SublimeText4) Name() string { return "sublime_text_4" }
+
+func (SublimeText4) PackagesDir() (string, error) {
+	return packagesDirFromMap(map[string]string{
+		"linux":   "$HOME/.config/sublime-text/Packages",
+		"darwin":  "$HOME/Library/Application Support/Sublime Text/Packages",
+		"windows": "$APPDATA\\Sublime Text\\Packages",
+	})
+}
+
+// Custom targets an arbitrary Packages directory, e.g. a Sublime Merge test
+// install or a fork that doesn't follow either ST3 or ST4's layout.
+type Custom struct {
+	Dir string
+}
+
+func (Custom) Name() string { return "custom" }
+
+func (c Custom) PackagesDir() (string, error) {
+	if c.Dir == "" {
+		return "", fmt.Errorf("custom editor requires -packages to be set")
+	}
+	if !filepath.IsAbs(c.Dir) {
+		return "", fmt.Errorf("packages dir `%s` is not absolute", c.Dir)
+	}
+	if _, err := os.Lstat(c.Dir); err != nil {
+		return "", fmt.Errorf("cannot stat packages dir `%s`: %s", c.Dir, err)
+	}
+	return c.Dir, nil
+}
+
+func packagesDirFromMap(byGOOS map[string]string) (string, error) {
+	dir := os.ExpandEnv(byGOOS[runtime.GOOS])
+	if dir == "" {
+		return "", fmt.Errorf("unsupported GOOS `%s`", runtime.GOOS)
+	}
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("packages dir `%s` is not absolute", dir)
+	}
+	if _, err := os.Lstat(dir); err != nil {
+		return "", fmt.Errorf("cannot stat packages dir `%s`: %s", dir, err)
+	}
+	return dir, nil
+}
+
+// ByName resolves the editor backend for name, which is expected to come
+// from the -editor flag. customDir is used as the Packages directory for the
+// "custom" backend.
+func ByName(name, customDir string) (Editor, error) {
+	switch name {
+	case "", "st3", "sublime_text_3":
+		return SublimeText3{}, nil
+	case "st4", "sublime_text_4":
+		return SublimeText4{}, nil
+	case "custom":
+		return Custom{Dir: customDir}, nil
+	default:
+		return nil, fmt.Errorf("unknown editor `%s` (want st3, st4, or custom)", name)
+	}
+}
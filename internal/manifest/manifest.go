@@ -0,0 +1,91 @@
+// Package manifest parses the optional gosublime-get config.toml that lets
+// users declare one or more GoSublime sources to install, each pinned to a
+// particular ref and, optionally, a known-good commit.
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// Config is the top-level shape of config.toml.
+type Config struct {
+	Sources []Source `toml:"source"`
+}
+
+// Source describes a single GoSublime checkout to install.
+type Source struct {
+	// Name is the directory under the editor's Packages dir the source is
+	// checked out into, e.g. "GoSublime".
+	Name string `toml:"name"`
+
+	// URL is the git remote to clone/fetch from.
+	URL string `toml:"url"`
+
+	// Ref is the branch or tag to check out, e.g. "development" or
+	// "v19.12.30".
+	Ref string `toml:"ref"`
+
+	// PinnedCommit, when set, is the sha1 HEAD must match after checkout.
+	// install aborts if it doesn't, rather than silently installing an
+	// unexpected commit.
+	PinnedCommit string `toml:"pinned_commit"`
+}
+
+// DefaultPath returns the default location of config.toml:
+// gosublime-get/config.toml under os.UserConfigDir, e.g.
+// ~/.config/gosublime-get/config.toml on Linux (respecting
+// $XDG_CONFIG_HOME), falling back to $HOME/.config when that fails.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.ExpandEnv("$HOME/.config")
+	}
+	return filepath.Join(dir, "gosublime-get", "config.toml")
+}
+
+// Load reads and parses the manifest at path. It returns an error satisfying
+// os.IsNotExist when path does not exist, so callers can fall back to
+// single-version installs.
+func Load(path string) (*Config, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	tree, err := toml.LoadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %s", path, err)
+	}
+	cfg := &Config{}
+	if err := tree.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %s", path, err)
+	}
+	for i, src := range cfg.Sources {
+		if src.Name == "" {
+			return nil, fmt.Errorf("%s: source #%d is missing `name`", path, i+1)
+		}
+		if !validSourceName(src.Name) {
+			return nil, fmt.Errorf("%s: source `%s` has an invalid `name` (must be a bare directory name, no path separators or `..`)", path, src.Name)
+		}
+		if src.URL == "" {
+			return nil, fmt.Errorf("%s: source `%s` is missing `url`", path, src.Name)
+		}
+		if src.Ref == "" {
+			return nil, fmt.Errorf("%s: source `%s` is missing `ref`", path, src.Name)
+		}
+	}
+	return cfg, nil
+}
+
+// validSourceName reports whether name is safe to join onto the Packages
+// dir: a single path element, not "." or "..", so a manifest can't be used
+// to write outside the Packages directory.
+func validSourceName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	return !strings.ContainsAny(name, `/\`)
+}
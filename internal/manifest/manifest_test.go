@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeManifest(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	return path
+}
+
+func TestLoadValid(t *testing.T) {
+	path := writeManifest(t, `
+[[source]]
+name = "GoSublime"
+url = "https://margo.sh/GoSublime"
+ref = "development"
+pinned_commit = "deadbeef"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if len(cfg.Sources) != 1 {
+		t.Fatalf("got %d sources, want 1", len(cfg.Sources))
+	}
+	src := cfg.Sources[0]
+	if src.Name != "GoSublime" || src.URL != "https://margo.sh/GoSublime" || src.Ref != "development" || src.PinnedCommit != "deadbeef" {
+		t.Fatalf("unexpected source: %+v", src)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "does-not-exist.toml"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("got err %v, want an os.IsNotExist error", err)
+	}
+}
+
+func TestLoadRejectsPathTraversal(t *testing.T) {
+	cases := []string{"../GoSublime", "..", "a/b", `a\b`, "/etc/passwd"}
+	for _, name := range cases {
+		// name is embedded as a TOML literal string (single quotes) so a
+		// literal backslash in a case like `a\b` isn't parsed as an escape.
+		path := writeManifest(t, `
+[[source]]
+name = '`+name+`'
+url = "https://margo.sh/GoSublime"
+ref = "development"
+`)
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load with name %q: got nil error, want rejection", name)
+		}
+	}
+}
+
+func TestLoadRequiresFields(t *testing.T) {
+	cases := []string{
+		`[[source]]
+url = "https://margo.sh/GoSublime"
+ref = "development"`,
+		`[[source]]
+name = "GoSublime"
+ref = "development"`,
+		`[[source]]
+name = "GoSublime"
+url = "https://margo.sh/GoSublime"`,
+	}
+	for _, body := range cases {
+		path := writeManifest(t, body)
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%q): got nil error, want a missing-field error", body)
+		}
+	}
+}
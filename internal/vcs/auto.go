@@ -0,0 +1,57 @@
+package vcs
+
+import (
+	"fmt"
+	"os"
+)
+
+// Auto tries Primary first and falls back to Fallback, step by step, when
+// Primary returns an error. This lets go-git stay the default while still
+// recovering from the edge cases it doesn't handle.
+type Auto struct {
+	Primary  VCS
+	Fallback VCS
+}
+
+func (a Auto) Name() string { return "auto" }
+
+func (a Auto) Clone(dir, url, remote, ref string, force bool) error {
+	err := a.Primary.Clone(dir, url, remote, ref, force)
+	if err == nil || err == ErrAlreadyExists {
+		return err
+	}
+	a.logFallback("clone", err)
+	return a.Fallback.Clone(dir, url, remote, ref, force)
+}
+
+func (a Auto) Fetch(dir, remote string, force bool) error {
+	err := a.Primary.Fetch(dir, remote, force)
+	if err == nil {
+		return nil
+	}
+	a.logFallback("fetch", err)
+	return a.Fallback.Fetch(dir, remote, force)
+}
+
+func (a Auto) Checkout(dir, ref string, force bool) error {
+	err := a.Primary.Checkout(dir, ref, force)
+	if err == nil {
+		return nil
+	}
+	a.logFallback("checkout", err)
+	return a.Fallback.Checkout(dir, ref, force)
+}
+
+func (a Auto) Pull(dir, remote, ref string) error {
+	err := a.Primary.Pull(dir, remote, ref)
+	if err == nil {
+		return nil
+	}
+	a.logFallback("pull", err)
+	return a.Fallback.Pull(dir, remote, ref)
+}
+
+func (a Auto) logFallback(step string, err error) {
+	fmt.Fprintf(os.Stderr, "gosublime-get: %s backend failed during %s (%s), falling back to %s\n",
+		a.Primary.Name(), step, err, a.Fallback.Name())
+}
@@ -0,0 +1,92 @@
+package vcs
+
+import (
+	"os"
+	"strings"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// GoGit implements VCS using the pure-Go gopkg.in/src-d/go-git.v4 library.
+// It's the default backend, requiring no external git installation.
+type GoGit struct{}
+
+func (GoGit) Name() string { return "go-git" }
+
+func (GoGit) Clone(dir, url, remote, ref string, force bool) error {
+	_, err := git.PlainClone(dir, false, &git.CloneOptions{
+		URL:           url,
+		ReferenceName: RefName(ref),
+		RemoteName:    remote,
+		Progress:      os.Stdout,
+	})
+	if err == git.ErrRepositoryAlreadyExists {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+func (GoGit) Fetch(dir, remote string, force bool) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	err = repo.Fetch(&git.FetchOptions{
+		Force:      force,
+		Progress:   os.Stdout,
+		RemoteName: remote,
+		Tags:       git.AllTags,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
+}
+
+func (GoGit) Checkout(dir, ref string, force bool) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	tree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	refName := RefName(ref)
+	err = tree.Checkout(&git.CheckoutOptions{Branch: refName, Force: force})
+	if err != nil {
+		err = tree.Checkout(&git.CheckoutOptions{Branch: refName, Force: force, Create: true})
+	}
+	return err
+}
+
+func (GoGit) Pull(dir, remote, ref string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	tree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	err = tree.Pull(&git.PullOptions{
+		RemoteName:    remote,
+		ReferenceName: RefName(ref),
+		Progress:      os.Stdout,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
+}
+
+// RefName turns a version string such as "development" or "v19.12.30" into
+// the plumbing.ReferenceName go-git expects: a tag when it looks like a
+// dotted version, a branch otherwise.
+func RefName(name string) plumbing.ReferenceName {
+	if strings.Contains(name, ".") {
+		return plumbing.NewTagReferenceName(name)
+	}
+	return plumbing.NewBranchReferenceName(name)
+}
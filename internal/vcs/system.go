@@ -0,0 +1,53 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// System implements VCS by shelling out to the system `git` executable. It's
+// an escape hatch for go-git v4 edge cases (corporate proxies, LFS, newer
+// smart-http protocol negotiation) that a real git binary handles fine.
+type System struct{}
+
+func (System) Name() string { return "system" }
+
+func (System) Clone(dir, url, remote, ref string, force bool) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return ErrAlreadyExists
+	}
+	return run("", "clone", "--origin", remote, "--branch", ref, url, dir)
+}
+
+func (System) Fetch(dir, remote string, force bool) error {
+	args := []string{"fetch", remote, "--tags"}
+	if force {
+		args = append(args, "--force")
+	}
+	return run(dir, args...)
+}
+
+func (System) Checkout(dir, ref string, force bool) error {
+	args := []string{"checkout"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, ref)
+	if err := run(dir, args...); err != nil {
+		return run(dir, "checkout", "-b", ref)
+	}
+	return nil
+}
+
+func (System) Pull(dir, remote, ref string) error {
+	return run(dir, "pull", "--ff-only", remote, ref)
+}
+
+func run(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
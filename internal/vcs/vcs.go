@@ -0,0 +1,48 @@
+// Package vcs abstracts over the git operations gosublime-get needs (clone,
+// fetch, checkout, pull) so that installs can fall back from go-git to the
+// system git binary when go-git can't handle a particular repository.
+package vcs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAlreadyExists is returned by Clone when dir already holds a checkout.
+var ErrAlreadyExists = errors.New("repository already exists")
+
+// VCS performs the git operations needed to install and update a GoSublime
+// checkout.
+type VCS interface {
+	// Name identifies the backend, e.g. "go-git".
+	Name() string
+
+	// Clone clones url into dir under remote, checking out ref. It returns
+	// ErrAlreadyExists if dir already holds a checkout.
+	Clone(dir, url, remote, ref string, force bool) error
+
+	// Fetch fetches remote's tags and refs into dir.
+	Fetch(dir, remote string, force bool) error
+
+	// Checkout checks dir out to ref, creating a local branch from the
+	// current HEAD if ref doesn't already exist.
+	Checkout(dir, ref string, force bool) error
+
+	// Pull fast-forwards dir's current branch from remote/ref.
+	Pull(dir, remote, ref string) error
+}
+
+// New resolves the VCS backend for name, which is expected to come from the
+// -vcs flag.
+func New(name string) (VCS, error) {
+	switch name {
+	case "", "go-git":
+		return GoGit{}, nil
+	case "system":
+		return System{}, nil
+	case "auto":
+		return Auto{Primary: GoGit{}, Fallback: System{}}, nil
+	default:
+		return nil, fmt.Errorf("unknown vcs backend `%s` (want go-git, system, or auto)", name)
+	}
+}
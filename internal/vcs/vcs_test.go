@@ -0,0 +1,42 @@
+package vcs
+
+import "testing"
+
+func TestRefName(t *testing.T) {
+	cases := []struct {
+		in       string
+		wantTag  bool
+		wantName string
+	}{
+		{"development", false, "refs/heads/development"},
+		{"next", false, "refs/heads/next"},
+		{"v19.12.30", true, "refs/tags/v19.12.30"},
+	}
+	for _, c := range cases {
+		ref := RefName(c.in)
+		if ref.IsTag() != c.wantTag {
+			t.Errorf("RefName(%q).IsTag() = %v, want %v", c.in, ref.IsTag(), c.wantTag)
+		}
+		if ref.String() != c.wantName {
+			t.Errorf("RefName(%q) = %q, want %q", c.in, ref.String(), c.wantName)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(""); err != nil {
+		t.Errorf("New(\"\"): %s", err)
+	}
+	if backend, err := New("go-git"); err != nil || backend.Name() != "go-git" {
+		t.Errorf("New(\"go-git\") = %v, %v", backend, err)
+	}
+	if backend, err := New("system"); err != nil || backend.Name() != "system" {
+		t.Errorf("New(\"system\") = %v, %v", backend, err)
+	}
+	if backend, err := New("auto"); err != nil || backend.Name() != "auto" {
+		t.Errorf("New(\"auto\") = %v, %v", backend, err)
+	}
+	if _, err := New("bogus"); err == nil {
+		t.Error("New(\"bogus\"): got nil error, want one")
+	}
+}
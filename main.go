@@ -5,14 +5,23 @@ import (
 	"fmt"
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/config"
-	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
+
+	"github.com/KurokuLabs/gosublime-get/internal/backup"
+	"github.com/KurokuLabs/gosublime-get/internal/editor"
+	"github.com/KurokuLabs/gosublime-get/internal/manifest"
+	"github.com/KurokuLabs/gosublime-get/internal/vcs"
 )
 
+const repoURL = "https://margo.sh/GoSublime"
+const remoteName = "gosublime-get"
+
 var (
 	logs = log.New(os.Stderr, "", 0)
 )
@@ -25,28 +34,69 @@ func main() {
 			subcmd = install
 		case "uninstall", "-R":
 			subcmd = uninstall
+		case "list", "-Q":
+			subcmd = list
+		case "ls-remote":
+			subcmd = lsRemote
+		case "use":
+			subcmd = use
+		case "doctor":
+			subcmd = doctor
 		}
 	}
 	if subcmd == nil {
-		logs.Printf("Usage: %s <install|uninstall>", os.Args[0])
+		logs.Printf("Usage: %s <install|uninstall|list|ls-remote|use|doctor>", os.Args[0])
 		os.Exit(1)
 	}
 	subcmd(flag.NewFlagSet(os.Args[0], flag.ExitOnError), os.Args[2:])
 }
 
 func install(flags *flag.FlagSet, args []string) {
-	packages, err := sublimePackagesDir()
-	if err != nil {
-		logs.Fatalln(err)
-	}
-
 	force := false
+	multi := false
 	version := "latest"
-	flags.StringVar(&packages, "packages", packages, "Path where Sublime Text 3 packages are stored.")
+	packages := ""
+	editorName := "st3"
+	configPath := manifest.DefaultPath()
+	vcsName := "go-git"
+	yes := false
+	backupDir := ""
+	flags.StringVar(&packages, "packages", packages, "Path where the editor's packages are stored. Defaults to the Packages dir resolved from -editor.")
+	flags.StringVar(&editorName, "editor", editorName, "Editor to install into: st3 (Sublime Text 3), st4 (Sublime Text 4), or custom (requires -packages).")
 	flags.StringVar(&version, "version", version, "The version tag (e.g. v19.12.30) or branch to install.\n  - Enter 'latest' to install the latest stable release branch (development).\n  - Enter 'beta' to install the unreleased development branch (next).")
 	flags.BoolVar(&force, "force", force, "Force apply git operations, even in cases where data might be overwritten.")
+	flags.BoolVar(&multi, "multi", multi, "Install into Packages/GoSublime@<version> instead of Packages/GoSublime, keeping other installed versions on disk.")
+	flags.StringVar(&configPath, "config", configPath, "Path to a gosublime-get config.toml listing one or more sources to install, overriding -version.")
+	flags.StringVar(&vcsName, "vcs", vcsName, "git backend to use: go-git (default, pure Go), system (shell out to the git executable), or auto (try go-git, fall back to system on error).")
+	flags.BoolVar(&yes, "yes", yes, "Skip the confirmation prompt before a -force checkout/pull overwrites an existing worktree.")
+	flags.StringVar(&backupDir, "backup", backupDir, "Directory to archive an existing worktree into as a timestamped .tar.gz before a -force checkout/pull.")
 	flags.Parse(args)
 
+	backend, err := vcs.New(vcsName)
+	if err != nil {
+		logs.Fatalln(err)
+	}
+
+	if packages == "" {
+		ed, err := editor.ByName(editorName, packages)
+		if err != nil {
+			logs.Fatalln(err)
+		}
+		packages, err = ed.PackagesDir()
+		if err != nil {
+			logs.Fatalln(err)
+		}
+	}
+
+	cfg, err := manifest.Load(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		logs.Fatalln(err)
+	}
+	if cfg != nil {
+		installManifest(packages, cfg, force, yes, backupDir, backend)
+		return
+	}
+
 	switch version {
 	case "latest":
 		version = "development"
@@ -54,124 +104,479 @@ func install(flags *flag.FlagSet, args []string) {
 		version = "next"
 	}
 
-	versionRef := referenceName(version)
-	gsDir := gosublimeDir(packages)
-	repoURL := "https://margo.sh/GoSublime"
-	remoteName := "gosublime-get"
-
-	repo, err := git.PlainClone(gsDir, false, &git.CloneOptions{
-		URL:           repoURL,
-		ReferenceName: versionRef,
-		RemoteName:    remoteName,
-		Progress:      os.Stdout,
-	})
-	if err == git.ErrRepositoryAlreadyExists {
-		repo, err = git.PlainOpen(gsDir)
-	}
-	if err != nil {
-		log.Fatalf("Cannot clone/open %s: %s\n", repoURL, err)
+	gsDir := filepath.Join(packages, gosublimeDirName(version, multi))
+	if _, err := cloneOrUpdate(gsDir, repoURL, version, force, yes, backupDir, backend); err != nil {
+		logs.Fatalln(err)
 	}
+	fmt.Printf("GoSublime installed in %s.\n", gsDir)
+	fmt.Println("You might need to restart Sublime Text for changes to take effect.", gsDir)
+}
 
-	if _, err := repo.Remote(remoteName); err != nil {
-		repo.CreateRemote(&config.RemoteConfig{
-			Name: remoteName,
-			URLs: []string{repoURL},
-		})
+// installManifest installs every source listed in cfg into packages,
+// verifying each source's pinned_commit (if set) once checked out.
+func installManifest(packages string, cfg *manifest.Config, force, yes bool, backupDir string, backend vcs.VCS) {
+	for _, src := range cfg.Sources {
+		gsDir := filepath.Join(packages, src.Name)
+		repo, err := cloneOrUpdate(gsDir, src.URL, src.Ref, force, yes, backupDir, backend)
+		if err != nil {
+			logs.Fatalln(err)
+		}
+		if src.PinnedCommit != "" {
+			head, err := repo.Head()
+			if err != nil {
+				logs.Fatalf("%s: cannot resolve HEAD: %s\n", src.Name, err)
+			}
+			if head.Hash().String() != src.PinnedCommit {
+				logs.Fatalf("%s: HEAD is %s, want pinned_commit %s\n", src.Name, head.Hash(), src.PinnedCommit)
+			}
+		}
+		fmt.Printf("%s installed in %s.\n", src.Name, gsDir)
 	}
+}
 
-	err = repo.Fetch(&git.FetchOptions{
-		Force:      force,
-		Progress:   os.Stdout,
-		RemoteName: remoteName,
-		Tags:       git.AllTags,
-	})
-	if err != nil && err != git.NoErrAlreadyUpToDate {
-		log.Fatalln("git fetch failed:", err)
+// cloneOrUpdate clones url into gsDir (or updates it if already cloned),
+// checks out ref, and pulls, using backend for the actual git operations so
+// it can be reused for both plain and manifest-driven installs, against
+// either go-git or the system git binary. When force would overwrite an
+// existing worktree, it's backed up to backupDir (if set) and confirmed
+// with the user (unless yes is set).
+func cloneOrUpdate(gsDir, url, ref string, force, yes bool, backupDir string, backend vcs.VCS) (*git.Repository, error) {
+	if _, err := os.Stat(gsDir); os.IsNotExist(err) {
+		if err := backend.Clone(gsDir, url, remoteName, ref, force); err != nil {
+			return nil, fmt.Errorf("cannot clone %s: %s", url, err)
+		}
+	} else if force {
+		if backupDir != "" {
+			dest := backup.TimestampedName(backupDir, gsDir)
+			if err := backup.Archive(gsDir, dest); err != nil {
+				return nil, fmt.Errorf("cannot back up %s: %s", gsDir, err)
+			}
+			fmt.Println("Backed up", gsDir, "to", dest)
+		}
+		if !yes && !confirm("This will force-overwrite local changes in %s.\n", gsDir) {
+			return nil, fmt.Errorf("aborted: %s left unchanged", gsDir)
+		}
 	}
 
-	tree, err := repo.Worktree()
-	if err != nil {
-		log.Fatalln("Failed to open git worktree:", err)
+	if err := ensureRemote(gsDir, remoteName, url); err != nil {
+		return nil, fmt.Errorf("cannot configure remote %s: %s", remoteName, err)
 	}
 
-	err = tree.Checkout(&git.CheckoutOptions{Branch: versionRef, Force: force})
-	if err != nil {
-		err := tree.Checkout(&git.CheckoutOptions{
-			Branch: versionRef,
-			Force:  force,
-			Create: true,
-		})
-		if err != nil {
-			log.Fatalln("git checkout failed:", err)
-		}
+	if err := backend.Fetch(gsDir, remoteName, force); err != nil {
+		return nil, fmt.Errorf("git fetch failed: %s", err)
 	}
 
-	err = tree.Pull(&git.PullOptions{
-		RemoteName:    remoteName,
-		ReferenceName: versionRef,
-		Progress:      os.Stdout,
-	})
-	if err != nil && err != git.NoErrAlreadyUpToDate {
-		log.Fatalf("git pull(%s) failed: %s\n", versionRef, err)
+	if err := backend.Checkout(gsDir, ref, force); err != nil {
+		return nil, fmt.Errorf("git checkout failed: %s", err)
 	}
-	fmt.Printf("GoSublime installed in %s.\n", gsDir)
-	fmt.Println("You might need to restart Sublime Text for changes to take effect.", gsDir)
-}
 
-func sublimePackagesDir() (string, error) {
-	dir := map[string]string{
-		"linux":   "$HOME/.config/sublime-text-3/Packages",
-		"darwin":  "$HOME/Library/Application Support/Sublime Text 3/Packages",
-		"windows": "$APPDATA\\Sublime Text 3\\Packages",
-	}[runtime.GOOS]
-	dir = os.ExpandEnv(dir)
-	if !filepath.IsAbs(dir) {
-		return "", fmt.Errorf("Packages dir `%s` is not absolute\n", dir)
+	if err := backend.Pull(gsDir, remoteName, ref); err != nil {
+		return nil, fmt.Errorf("git pull(%s) failed: %s", ref, err)
 	}
-	if _, err := os.Lstat(dir); err != nil {
-		return "", fmt.Errorf("Cannot stat packages dir `%s`: %s\n", dir, err)
+
+	repo, err := git.PlainOpen(gsDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s: %s", gsDir, err)
 	}
-	return dir, nil
+	return repo, nil
 }
 
-func referenceName(name string) plumbing.ReferenceName {
-	if strings.Contains(name, ".") {
-		return plumbing.NewTagReferenceName(name)
+// ensureRemote makes sure dir has a remote named name pointing at url,
+// creating it if an older checkout (or a backend that names it differently)
+// left it missing.
+func ensureRemote(dir, name, url string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
 	}
-	return plumbing.NewBranchReferenceName(name)
+	if remote, err := repo.Remote(name); err == nil {
+		if urls := remote.Config().URLs; len(urls) != 0 && urls[0] == url {
+			return nil
+		}
+		if err := repo.DeleteRemote(name); err != nil {
+			return err
+		}
+	}
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}})
+	return err
 }
 
 func uninstall(flags *flag.FlagSet, args []string) {
-	packages, err := sublimePackagesDir()
-	if err != nil {
-		logs.Fatalln(err)
-	}
-	flags.StringVar(&packages, "packages", packages, "Path where Sublime Text 3 packages are stored.")
+	packages := ""
+	editorName := "st3"
+	yes := false
+	dryRun := false
+	backupDir := ""
+	flags.StringVar(&packages, "packages", packages, "Path where the editor's packages are stored. Defaults to the Packages dir resolved from -editor.")
+	flags.StringVar(&editorName, "editor", editorName, "Editor to uninstall from: st3 (Sublime Text 3), st4 (Sublime Text 4), or custom (requires -packages).")
+	flags.BoolVar(&yes, "yes", yes, "Skip the confirmation prompt (for scripted/CI use).")
+	flags.BoolVar(&dryRun, "dry-run", dryRun, "Print what would be removed, without removing anything.")
+	flags.StringVar(&backupDir, "backup", backupDir, "Directory to archive the install into as a timestamped .tar.gz before removing it.")
 	flags.Parse(args)
 
+	if packages == "" {
+		ed, err := editor.ByName(editorName, packages)
+		if err != nil {
+			logs.Fatalln(err)
+		}
+		packages, err = ed.PackagesDir()
+		if err != nil {
+			logs.Fatalln(err)
+		}
+	}
+
 	gsDir := gosublimeDir(packages)
 	fi, err := os.Stat(gsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			logs.Fatalf("GoSublime not installed: stat(%s): %s\n", gsDir, err)
 		}
-		logs.Fatalf("GoSublime not installed in %s\n", gsDir, err)
+		logs.Fatalf("GoSublime not installed in %s: %s\n", gsDir, err)
 	}
 	if !fi.IsDir() {
 		logs.Fatalf("%s is not a directory: %s\n", gsDir, err)
 	}
-	confirm := ""
-	fmt.Printf("Are you sure you want to remove directory %s?\nEnter 'Y' or 'y' to confirm: ", gsDir)
-	fmt.Scanf("%s", &confirm)
-	if confirm != "Y" && confirm != "y" {
+
+	if dryRun {
+		fmt.Println("Would remove", gsDir)
 		return
 	}
+
+	if !yes && !confirm("Are you sure you want to remove directory %s?\n", gsDir) {
+		return
+	}
+
+	if backupDir != "" {
+		dest := backup.TimestampedName(backupDir, gsDir)
+		if err := backup.Archive(gsDir, dest); err != nil {
+			logs.Fatalf("Cannot back up %s: %s\n", gsDir, err)
+		}
+		fmt.Println("Backed up", gsDir, "to", dest)
+	}
+
 	if err := os.RemoveAll(gsDir); err != nil {
 		logs.Fatalf("Cannot remove %s: %s\n", gsDir, err)
 	}
 	fmt.Println(gsDir, "removed")
 }
 
+// confirm prints prompt (with format args applied) followed by a
+// yes/no hint, and reports whether the user answered 'Y' or 'y'.
+func confirm(prompt string, args ...interface{}) bool {
+	fmt.Printf(prompt, args...)
+	fmt.Print("Enter 'Y' or 'y' to confirm: ")
+	answer := ""
+	fmt.Scanf("%s", &answer)
+	return answer == "Y" || answer == "y"
+}
+
 func gosublimeDir(packagesDir string) string {
 	return filepath.Join(packagesDir, "GoSublime")
 }
+
+// gosublimeDirName returns the directory name GoSublime should be checked
+// out under: "GoSublime" normally, or "GoSublime@<version>" when multi is
+// set so that several versions can coexist on disk.
+func gosublimeDirName(version string, multi bool) string {
+	if multi {
+		return fmt.Sprintf("GoSublime@%s", version)
+	}
+	return "GoSublime"
+}
+
+// list prints the GoSublime checkouts found directly under the editor's
+// Packages directory (both the plain "GoSublime" install and any
+// "GoSublime@<version>" installs made with -multi), marking the one that
+// Sublime Text actually loads with a leading '*'.
+func list(flags *flag.FlagSet, args []string) {
+	packages := ""
+	editorName := "st3"
+	flags.StringVar(&packages, "packages", packages, "Path where the editor's packages are stored. Defaults to the Packages dir resolved from -editor.")
+	flags.StringVar(&editorName, "editor", editorName, "Editor to inspect: st3 (Sublime Text 3), st4 (Sublime Text 4), or custom (requires -packages).")
+	flags.Parse(args)
+
+	if packages == "" {
+		ed, err := editor.ByName(editorName, packages)
+		if err != nil {
+			logs.Fatalln(err)
+		}
+		packages, err = ed.PackagesDir()
+		if err != nil {
+			logs.Fatalln(err)
+		}
+	}
+
+	entries, err := os.ReadDir(packages)
+	if err != nil {
+		logs.Fatalf("Cannot read packages dir `%s`: %s\n", packages, err)
+	}
+
+	found := false
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || (name != "GoSublime" && !strings.HasPrefix(name, "GoSublime@")) {
+			continue
+		}
+		repo, err := git.PlainOpen(filepath.Join(packages, name))
+		if err != nil {
+			continue
+		}
+		found = true
+		head, err := repo.Head()
+		version := "?"
+		if err == nil {
+			version = head.Name().Short()
+		}
+		marker := " "
+		if name == "GoSublime" {
+			marker = "*"
+		}
+		fmt.Printf("%s %-24s %s\n", marker, name, version)
+	}
+	if !found {
+		fmt.Println("No GoSublime checkouts found in", packages)
+	}
+}
+
+// lsRemote lists the tags and branches available on the gosublime-get
+// remote, without requiring a local checkout.
+func lsRemote(flags *flag.FlagSet, args []string) {
+	stable := false
+	all := false
+	flags.BoolVar(&stable, "stable", stable, "List only stable release tags, hiding branches.")
+	flags.BoolVar(&all, "all", all, "List every remote branch, not just 'development' and 'next'.")
+	flags.Parse(args)
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{repoURL},
+	})
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		logs.Fatalln("git ls-remote failed:", err)
+	}
+
+	for _, ref := range refs {
+		name := ref.Name()
+		switch {
+		case name.IsTag():
+			fmt.Println(name.Short())
+		case name.IsBranch() && !stable:
+			if all || name.Short() == "development" || name.Short() == "next" {
+				fmt.Println(name.Short())
+			}
+		}
+	}
+}
+
+// use switches an already-cloned GoSublime worktree to version without
+// re-cloning or re-fetching, giving a quick rollback path to a known-good
+// version or branch.
+func use(flags *flag.FlagSet, args []string) {
+	packages := ""
+	editorName := "st3"
+	flags.StringVar(&packages, "packages", packages, "Path where the editor's packages are stored. Defaults to the Packages dir resolved from -editor.")
+	flags.StringVar(&editorName, "editor", editorName, "Editor whose install to switch: st3 (Sublime Text 3), st4 (Sublime Text 4), or custom (requires -packages).")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		logs.Fatalf("Usage: %s use <version>\n", os.Args[0])
+	}
+	version := flags.Arg(0)
+	switch version {
+	case "latest":
+		version = "development"
+	case "beta":
+		version = "next"
+	}
+
+	if packages == "" {
+		ed, err := editor.ByName(editorName, packages)
+		if err != nil {
+			logs.Fatalln(err)
+		}
+		packages, err = ed.PackagesDir()
+		if err != nil {
+			logs.Fatalln(err)
+		}
+	}
+
+	gsDir := gosublimeDir(packages)
+	repo, err := git.PlainOpen(gsDir)
+	if err != nil {
+		logs.Fatalf("GoSublime not installed in %s: %s\n", gsDir, err)
+	}
+	tree, err := repo.Worktree()
+	if err != nil {
+		logs.Fatalln("Failed to open git worktree:", err)
+	}
+	if err := tree.Checkout(&git.CheckoutOptions{Branch: vcs.RefName(version)}); err != nil {
+		logs.Fatalln("git checkout failed:", err)
+	}
+	fmt.Printf("Switched %s to %s.\n", gsDir, version)
+}
+
+// doctor diagnoses common ways a GoSublime worktree can break and, with
+// -fix, repairs what it can: a missing/wrong remote, an orphaned
+// .git/index.lock, and (after stashing local changes) a re-run of the same
+// checkout/pull sequence install uses.
+func doctor(flags *flag.FlagSet, args []string) {
+	packages := ""
+	editorName := "st3"
+	fix := false
+	backupDir := ""
+	flags.StringVar(&packages, "packages", packages, "Path where the editor's packages are stored. Defaults to the Packages dir resolved from -editor.")
+	flags.StringVar(&editorName, "editor", editorName, "Editor to diagnose: st3 (Sublime Text 3), st4 (Sublime Text 4), or custom (requires -packages).")
+	flags.BoolVar(&fix, "fix", fix, "Attempt to repair the issues found.")
+	flags.StringVar(&backupDir, "backup", backupDir, "Directory to archive the worktree into as a timestamped .tar.gz before -fix re-runs checkout/pull.")
+	flags.Parse(args)
+
+	if packages == "" {
+		ed, err := editor.ByName(editorName, packages)
+		if err != nil {
+			logs.Fatalln(err)
+		}
+		packages, err = ed.PackagesDir()
+		if err != nil {
+			logs.Fatalln(err)
+		}
+	}
+
+	gsDir := gosublimeDir(packages)
+	repo, err := git.PlainOpen(gsDir)
+	if err != nil {
+		logs.Fatalf("GoSublime not installed in %s: %s\n", gsDir, err)
+	}
+
+	healthy := true
+
+	if remote, err := repo.Remote(remoteName); err != nil {
+		healthy = false
+		fmt.Printf("[FAIL] remote `%s` is missing\n", remoteName)
+		if fix {
+			if _, err := repo.CreateRemote(&config.RemoteConfig{Name: remoteName, URLs: []string{repoURL}}); err != nil {
+				logs.Fatalf("Cannot recreate remote %s: %s\n", remoteName, err)
+			}
+			fmt.Printf("[FIX] recreated remote `%s` -> %s\n", remoteName, repoURL)
+		}
+	} else if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != repoURL {
+		healthy = false
+		fmt.Printf("[FAIL] remote `%s` points at %v, want %s\n", remoteName, urls, repoURL)
+		if fix {
+			if err := repo.DeleteRemote(remoteName); err != nil {
+				logs.Fatalf("Cannot remove remote %s: %s\n", remoteName, err)
+			}
+			if _, err := repo.CreateRemote(&config.RemoteConfig{Name: remoteName, URLs: []string{repoURL}}); err != nil {
+				logs.Fatalf("Cannot recreate remote %s: %s\n", remoteName, err)
+			}
+			fmt.Printf("[FIX] reset remote `%s` -> %s\n", remoteName, repoURL)
+		}
+	} else {
+		fmt.Printf("[OK] remote `%s` -> %s\n", remoteName, repoURL)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		healthy = false
+		fmt.Println("[FAIL] cannot resolve HEAD:", err)
+	} else if head.Name().IsBranch() || head.Name().IsTag() {
+		fmt.Println("[OK] HEAD is on", head.Name().Short())
+	} else {
+		healthy = false
+		fmt.Println("[WARN] HEAD is detached at", head.Hash())
+	}
+
+	tree, err := repo.Worktree()
+	if err != nil {
+		logs.Fatalln("Failed to open git worktree:", err)
+	}
+	worktreeSafe := true
+	status, err := tree.Status()
+	if err != nil {
+		healthy = false
+		fmt.Println("[FAIL] cannot read worktree status:", err)
+	} else if status.IsClean() {
+		fmt.Println("[OK] worktree is clean")
+	} else {
+		healthy = false
+		worktreeSafe = false
+		fmt.Println("[WARN] uncommitted local changes:")
+		for path, s := range status {
+			fmt.Printf("       %c%c %s\n", s.Staging, s.Worktree, path)
+		}
+		if fix && confirm("Stash local changes in %s before continuing?\n", gsDir) {
+			if err := runGit(gsDir, "stash"); err != nil {
+				logs.Fatalf("Cannot stash changes in %s: %s\n", gsDir, err)
+			}
+			fmt.Println("[FIX] stashed local changes in", gsDir)
+			worktreeSafe = true
+		}
+	}
+
+	if head != nil {
+		if commit, err := repo.CommitObject(head.Hash()); err == nil {
+			if commitTree, err := commit.Tree(); err == nil {
+				missing := []string{}
+				commitTree.Files().ForEach(func(f *object.File) error {
+					if _, err := os.Stat(filepath.Join(gsDir, f.Name)); os.IsNotExist(err) {
+						missing = append(missing, f.Name)
+					}
+					return nil
+				})
+				if len(missing) == 0 {
+					fmt.Println("[OK] no files missing versus HEAD")
+				} else {
+					healthy = false
+					fmt.Println("[FAIL] files missing versus HEAD:")
+					for _, f := range missing {
+						fmt.Println("      ", f)
+					}
+				}
+			}
+		}
+	}
+
+	lockPath := filepath.Join(gsDir, ".git", "index.lock")
+	if _, err := os.Stat(lockPath); err == nil {
+		healthy = false
+		fmt.Println("[FAIL] orphaned lock file:", lockPath)
+		if fix {
+			if err := os.Remove(lockPath); err != nil {
+				logs.Fatalf("Cannot remove %s: %s\n", lockPath, err)
+			}
+			fmt.Println("[FIX] removed", lockPath)
+		}
+	} else {
+		fmt.Println("[OK] no orphaned index.lock")
+	}
+
+	if fix {
+		if !worktreeSafe && !confirm("Uncommitted changes remain in %s; force checkout/pull anyway? This will overwrite them.\n", gsDir) {
+			fmt.Println("Skipping checkout/pull repair; uncommitted changes in", gsDir, "were left untouched.")
+			return
+		}
+		ref := "development"
+		if head != nil && (head.Name().IsBranch() || head.Name().IsTag()) {
+			ref = head.Name().Short()
+		}
+		if _, err := cloneOrUpdate(gsDir, repoURL, ref, true, true, backupDir, vcs.GoGit{}); err != nil {
+			logs.Fatalln(err)
+		}
+		fmt.Println("[FIX] re-ran checkout/pull for", ref)
+		return
+	}
+
+	if healthy {
+		fmt.Println("GoSublime install looks healthy.")
+	} else {
+		fmt.Println("Issues found above; re-run with -fix to attempt repair.")
+	}
+}
+
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}